@@ -11,17 +11,43 @@ import (
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
+// actionUpload uploads a new app artifact to the configured track, the
+// step's original (and default) behavior.
+const actionUpload = "upload"
+
+// actionHalt sets the current track's in-progress release to the `halted`
+// status, pausing a staged rollout without re-uploading anything.
+const actionHalt = "halt"
+
+// actionResume flips a `halted` release back to `inProgress`, resuming a
+// staged rollout from where it left off.
+const actionResume = "resume"
+
+// actionUpdateRollout updates the userFraction/inAppUpdatePriority of the
+// current track's in-progress release, without touching its APKs/AABs.
+const actionUpdateRollout = "update_rollout"
+
+// actionPromote moves the versionCodes currently live on SourceTrack onto
+// Track, at UserFraction, without re-uploading bytes.
+const actionPromote = "promote"
+
 // Configs stores the step's inputs
 type Configs struct {
-	JSONKeyPath       stepconf.Secret `env:"service_account_json_key_path,required"`
-	PackageName       string          `env:"package_name,required"`
-	AppPath           string          `env:"app_path,required"`
-	ExpansionfilePath string          `env:"expansionfile_path"`
-	Track             string          `env:"track,required"`
-	UserFraction      float64         `env:"user_fraction,range]0.0..1.0["`
-	UpdatePriority    int             `env:"update_priority,range[0..5]"`
-	WhatsnewsDir      string          `env:"whatsnews_dir"`
-	MappingFile       string          `env:"mapping_file"`
+	JSONKeyPath             stepconf.Secret `env:"service_account_json_key_path,required"`
+	PackageName             string          `env:"package_name,required"`
+	Action                  string          `env:"action,opt[upload,halt,resume,update_rollout,promote]"`
+	AppPath                 string          `env:"app_path"`
+	SplitAPKs               bool            `env:"split_apks,opt[true,false]"`
+	ExpansionfilePath       string          `env:"expansionfile_path"`
+	Track                   string          `env:"track,required"`
+	SourceTrack             string          `env:"source_track"`
+	StrictTrack             bool            `env:"strict_track,opt[true,false]"`
+	UserFraction            float64         `env:"user_fraction,range]0.0..1.0["`
+	UpdatePriority          int             `env:"update_priority,range[0..5]"`
+	WhatsnewsDir            string          `env:"whatsnews_dir"`
+	WhatsnewsFallbackLocale string          `env:"whatsnews_fallback_locale"`
+	WhatsnewsStrict         bool            `env:"whatsnews_strict,opt[true,false]"`
+	MappingFile             string          `env:"mapping_file"`
 }
 
 // validate validates the Configs.
@@ -38,7 +64,38 @@ func (c Configs) validate() error {
 		return err
 	}
 
-	return c.validateApps()
+	return c.validateAction()
+}
+
+// validateAction validates the inputs required by the configured action. Only
+// `upload` (the default) re-uploads app artifacts and so only it requires
+// app_path; the other actions operate on a release already on the track.
+func (c Configs) validateAction() error {
+	switch c.action() {
+	case actionUpload:
+		return c.validateApps()
+	case actionHalt, actionResume, actionUpdateRollout:
+		return nil
+	case actionPromote:
+		if c.SourceTrack == "" {
+			return errors.New("source_track is required when action is promote")
+		}
+		if c.SourceTrack == c.Track {
+			return errors.New("source_track and track must differ when action is promote")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported action: %s", c.Action)
+	}
+}
+
+// action returns the configured action, defaulting to actionUpload when
+// action is left empty.
+func (c Configs) action() string {
+	if c.Action == "" {
+		return actionUpload
+	}
+	return c.Action
 }
 
 // validateJSONKeyPath validates if service_account_json_key_path input value exists if defined and has file:// URL scheme.
@@ -56,7 +113,10 @@ func (c Configs) validateJSONKeyPath() error {
 	return nil
 }
 
-// validateWhatsnewsDir validates if whatsnews_dir input value exists if provided.
+// validateWhatsnewsDir validates if whatsnews_dir input value exists if
+// provided, and, if so, that its contents are well-formed release notes:
+// one BCP-47-named file per locale, each within Google Play's 500 character
+// limit.
 func (c Configs) validateWhatsnewsDir() error {
 	if c.WhatsnewsDir == "" {
 		return nil
@@ -67,7 +127,8 @@ func (c Configs) validateWhatsnewsDir() error {
 	} else if !exist {
 		return errors.New("what's new directory not exist at: " + c.WhatsnewsDir)
 	}
-	return nil
+
+	return c.validateWhatsnews()
 }
 
 // validateMappingFile validates if mapping_file input value exists if provided.
@@ -113,7 +174,34 @@ func parseAppList(list string) (apps []string) {
 	return
 }
 
-// appPaths returns the app to deploy, by preferring .aab files.
+// splitAPKSuffixes lists the filename suffixes (before the .apk extension)
+// Android uses for ABI and screen-density configuration splits, e.g.
+// "app-arm64-v8a.apk" or "app-xxhdpi.apk".
+var splitAPKSuffixes = []string{
+	"arm64-v8a", "armeabi-v7a", "armeabi", "x86_64", "x86", "mips64", "mips",
+	"ldpi", "mdpi", "hdpi", "xhdpi", "xxhdpi", "xxxhdpi", "nodpi", "tvdpi",
+}
+
+const universalAPKSuffix = "universal"
+
+// splitSuffix returns the ABI/density suffix (or "universal") of an APK
+// filename (without its extension), and whether one was found.
+// "app-arm64-v8a.apk" -> "arm64-v8a".
+func splitSuffix(pth string) (string, bool) {
+	name := strings.TrimSuffix(filepath.Base(pth), filepath.Ext(pth))
+	for _, suffix := range append(append([]string{}, splitAPKSuffixes...), universalAPKSuffix) {
+		if strings.HasSuffix(name, "-"+suffix) {
+			return suffix, true
+		}
+	}
+	return "", false
+}
+
+// appPaths returns the app(s) to deploy, by preferring .aab files. A set of
+// ABI/density APK splits (optionally accompanied by a universal APK) is
+// returned together as siblings of the same release, either because
+// split_apks is set or because their filenames carry the conventional
+// "-<abi>"/"-<density>" suffixes.
 func (c Configs) appPaths() ([]string, []string) {
 	var apks, aabs, warnings []string
 	for _, pth := range parseAppList(c.AppPath) {
@@ -142,30 +230,94 @@ func (c Configs) appPaths() ([]string, []string) {
 		return aabs[:1], warnings
 	}
 
+	if len(apks) > 1 {
+		splits, splitWarnings := splitAPKs(apks, c.SplitAPKs)
+		warnings = append(warnings, splitWarnings...)
+		if len(splits) > 0 {
+			return splits, warnings
+		}
+	}
+
 	return apks, warnings
 }
 
+// splitAPKs recognizes apks as a set of ABI/density splits (of the same
+// release) when at least one .apk carries a recognized "-<abi>"/"-<density>"
+// suffix and at most one does not (e.g. a "-universal" sibling). It returns
+// the apks unchanged when they form such a set. Otherwise it returns no apks,
+// with a warning when more than one .apk lacks a split suffix, or when
+// split_apks is set but none carries one; split_apks does not change which
+// sets are recognized.
+func splitAPKs(apks []string, splitAPKsEnabled bool) ([]string, []string) {
+	var suffixed, unsuffixed []string
+	for _, pth := range apks {
+		if suffix, ok := splitSuffix(pth); ok && suffix != universalAPKSuffix {
+			suffixed = append(suffixed, pth)
+		} else {
+			unsuffixed = append(unsuffixed, pth)
+		}
+	}
+
+	switch {
+	case len(suffixed) == 0:
+		if splitAPKsEnabled {
+			return nil, []string{"split_apks is set but no APK filename carries a recognized ABI/density suffix (e.g. app-arm64-v8a.apk)"}
+		}
+		return nil, nil
+	case len(unsuffixed) > 1:
+		return nil, []string{fmt.Sprintf("more than 1 non-split APK alongside ABI/density splits, expected at most one universal APK: %s", strings.Join(unsuffixed, ", "))}
+	}
+
+	log.Infof("Found %d APK split(s) for the same release: %s", len(apks), strings.Join(apks, ", "))
+	return apks, nil
+}
+
+// appRelease is the set of app artifacts resolved for a single release,
+// together with the versionCode declared by each, as read from their
+// manifests during validation. VersionCodes only covers the artifacts whose
+// manifest could be decoded (see validateAppManifests), so it may be shorter
+// than Paths. upload composes the track release from the versionCodes Google
+// Play returns for each uploaded artifact, not from VersionCodes.
+type appRelease struct {
+	Paths        []string
+	VersionCodes []int64
+}
+
 // validateApps validates if files provided via app_path are existing files,
 // if app_path is empty it validates if files provided via app_path input are existing .apk or .aab files.
 func (c Configs) validateApps() error {
+	_, err := c.resolveAppRelease()
+	return err
+}
+
+// resolveAppRelease validates the app artifacts provided via app_path and
+// returns the resolved appRelease: the paths to upload and the versionCode
+// declared by each, read from their manifests before any Edit is created on
+// Google Play.
+func (c Configs) resolveAppRelease() (appRelease, error) {
 	apps, warnings := c.appPaths()
 	for _, warn := range warnings {
 		log.Warnf(warn)
 	}
 
 	if len(apps) == 0 {
-		return fmt.Errorf("no app provided")
+		return appRelease{}, fmt.Errorf("no app provided")
 	}
 
 	for _, pth := range apps {
 		if exist, err := pathutil.IsPathExists(pth); err != nil {
-			return fmt.Errorf("failed to check if app exist at: %s, error: %s", pth, err)
+			return appRelease{}, fmt.Errorf("failed to check if app exist at: %s, error: %s", pth, err)
 		} else if !exist {
-			return errors.New("app not exist at: " + pth)
+			return appRelease{}, errors.New("app not exist at: " + pth)
 		}
 	}
 
-	return nil
+	versionCodes, err := c.validateAppManifests(apps)
+	if err != nil {
+		return appRelease{}, err
+	}
+
+	return appRelease{Paths: apps, VersionCodes: versionCodes}, nil
 }
 
 // expansionFiles gets the expansion files from the received configuration. Returns true and the entries (type and
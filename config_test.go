@@ -178,6 +178,47 @@ func TestConfigs_appPaths(t *testing.T) {
 			wantApps:     []string{"/bitrise/deploy/app-bitrise-signed.aab"},
 			wantWarnings: []string{"More than 1 .aab files provided, using the first: /bitrise/deploy/app-bitrise-signed.aab"},
 		},
+		{
+			name: "ABI split APKs detected by suffix",
+			config: Configs{
+				AppPath: "app-arm64-v8a.apk|app-armeabi-v7a.apk|app-x86_64.apk",
+			},
+			wantApps:     []string{"app-arm64-v8a.apk", "app-armeabi-v7a.apk", "app-x86_64.apk"},
+			wantWarnings: nil,
+		},
+		{
+			name: "ABI splits plus a universal APK",
+			config: Configs{
+				AppPath: "app-arm64-v8a.apk|app-armeabi-v7a.apk|app-universal.apk",
+			},
+			wantApps:     []string{"app-arm64-v8a.apk", "app-armeabi-v7a.apk", "app-universal.apk"},
+			wantWarnings: nil,
+		},
+		{
+			name: "split_apks set with no recognizable suffix",
+			config: Configs{
+				AppPath:   "app1.apk|app2.apk",
+				SplitAPKs: true,
+			},
+			wantApps:     []string{"app1.apk", "app2.apk"},
+			wantWarnings: []string{"split_apks is set but no APK filename carries a recognized ABI/density suffix (e.g. app-arm64-v8a.apk)"},
+		},
+		{
+			name: "more than one non-split APK alongside ABI splits",
+			config: Configs{
+				AppPath: "app-arm64-v8a.apk|app.apk|app-debug.apk",
+			},
+			wantApps:     []string{"app-arm64-v8a.apk", "app.apk", "app-debug.apk"},
+			wantWarnings: []string{"more than 1 non-split APK alongside ABI/density splits, expected at most one universal APK: app.apk, app-debug.apk"},
+		},
+		{
+			name: "multiple plain apks without split suffixes",
+			config: Configs{
+				AppPath: "app1.apk|app2.apk",
+			},
+			wantApps:     []string{"app1.apk", "app2.apk"},
+			wantWarnings: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -192,6 +233,90 @@ func TestConfigs_appPaths(t *testing.T) {
 	}
 }
 
+func Test_splitSuffix(t *testing.T) {
+	tests := []struct {
+		name       string
+		pth        string
+		wantSuffix string
+		wantOK     bool
+	}{
+		{name: "abi suffix", pth: "app-arm64-v8a.apk", wantSuffix: "arm64-v8a", wantOK: true},
+		{name: "density suffix", pth: "/bitrise/deploy/app-xxhdpi.apk", wantSuffix: "xxhdpi", wantOK: true},
+		{name: "universal suffix", pth: "app-universal.apk", wantSuffix: "universal", wantOK: true},
+		{name: "no suffix", pth: "app.apk", wantSuffix: "", wantOK: false},
+		{name: "unrelated suffix", pth: "app-debug.apk", wantSuffix: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSuffix, gotOK := splitSuffix(tt.pth)
+			if gotSuffix != tt.wantSuffix || gotOK != tt.wantOK {
+				t.Errorf("splitSuffix(%q) = (%q, %v), want (%q, %v)", tt.pth, gotSuffix, gotOK, tt.wantSuffix, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestConfigs_validateAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Configs
+		wantErr bool
+	}{
+		{
+			name:    "upload requires an existing app",
+			config:  Configs{Action: actionUpload, AppPath: ""},
+			wantErr: true,
+		},
+		{
+			name:    "empty action defaults to upload",
+			config:  Configs{Action: "", AppPath: ""},
+			wantErr: true,
+		},
+		{
+			name:    "halt does not require app_path",
+			config:  Configs{Action: actionHalt, Track: "production"},
+			wantErr: false,
+		},
+		{
+			name:    "resume does not require app_path",
+			config:  Configs{Action: actionResume, Track: "production"},
+			wantErr: false,
+		},
+		{
+			name:    "update_rollout does not require app_path",
+			config:  Configs{Action: actionUpdateRollout, Track: "production"},
+			wantErr: false,
+		},
+		{
+			name:    "promote requires source_track",
+			config:  Configs{Action: actionPromote, Track: "beta"},
+			wantErr: true,
+		},
+		{
+			name:    "promote requires source_track to differ from track",
+			config:  Configs{Action: actionPromote, Track: "beta", SourceTrack: "beta"},
+			wantErr: true,
+		},
+		{
+			name:    "promote with distinct tracks",
+			config:  Configs{Action: actionPromote, Track: "beta", SourceTrack: "internal"},
+			wantErr: false,
+		},
+		{
+			name:    "unknown action",
+			config:  Configs{Action: "rollback"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.validateAction(); (err != nil) != tt.wantErr {
+				t.Errorf("Configs.validateAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func Test_expansionFiles(t *testing.T) {
 	tests := []struct {
 		name                    string
@@ -0,0 +1,244 @@
+// Package axml decodes the small subset of Android's binary XML (AXML) format
+// needed to read package metadata out of an APK's AndroidManifest.xml, without
+// pulling in aapt or the Android SDK.
+package axml
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+)
+
+const (
+	chunkXML          = 0x00080003
+	chunkStringPool   = 0x0001
+	chunkResourceMap  = 0x0180
+	chunkStartElement = 0x0102
+
+	stringPoolFlagUTF8 = 1 << 8
+
+	// resource IDs of the manifest attributes we care about, as assigned by
+	// the Android platform (see android.R.attr).
+	attrVersionCode = 0x0101021b
+	attrVersionName = 0x0101021c
+)
+
+// Manifest holds the subset of AndroidManifest.xml fields this step validates.
+type Manifest struct {
+	Package     string
+	VersionCode int64
+	VersionName string
+}
+
+// Decode parses a binary AndroidManifest.xml (as found at the root of an APK)
+// and returns the package name and version fields declared on the root
+// <manifest> element.
+func Decode(data []byte) (Manifest, error) {
+	if len(data) < 8 || binary.LittleEndian.Uint32(data[0:4]) != chunkXML {
+		return Manifest{}, fmt.Errorf("not a binary AndroidManifest.xml")
+	}
+
+	var (
+		pool        []string
+		resourceMap []uint32
+		manifest    Manifest
+		foundTag    bool
+	)
+
+	off := 8 // skip the XML chunk header (type+headerSize, size)
+	for off+8 <= len(data) {
+		chunkType := binary.LittleEndian.Uint16(data[off:])
+		headerSize := binary.LittleEndian.Uint16(data[off+2:])
+		chunkSize := binary.LittleEndian.Uint32(data[off+4:])
+		if chunkSize == 0 || int(chunkSize) > len(data)-off {
+			return Manifest{}, fmt.Errorf("corrupt AXML chunk at offset %d", off)
+		}
+		body := data[off : off+int(chunkSize)]
+
+		switch chunkType {
+		case chunkStringPool:
+			var err error
+			pool, err = decodeStringPool(body, int(headerSize))
+			if err != nil {
+				return Manifest{}, err
+			}
+		case chunkResourceMap:
+			resourceMap = decodeResourceMap(body[headerSize:])
+		case chunkStartElement:
+			name, attrs := decodeStartElement(body[headerSize:], pool, resourceMap)
+			if name == "manifest" {
+				manifest = manifestFromAttrs(attrs, pool, resourceMap)
+				foundTag = true
+			}
+		}
+
+		if foundTag {
+			break
+		}
+		off += int(chunkSize)
+	}
+
+	if !foundTag {
+		return Manifest{}, fmt.Errorf("<manifest> element not found in AndroidManifest.xml")
+	}
+	return manifest, nil
+}
+
+func decodeStringPool(chunk []byte, headerSize int) ([]string, error) {
+	if len(chunk) < 28 {
+		return nil, fmt.Errorf("corrupt string pool chunk")
+	}
+	stringCount := binary.LittleEndian.Uint32(chunk[8:])
+	flags := binary.LittleEndian.Uint32(chunk[16:])
+	stringsStart := binary.LittleEndian.Uint32(chunk[20:])
+	isUTF8 := flags&stringPoolFlagUTF8 != 0
+
+	// The offsets table immediately follows the ResStringPool_header struct.
+	offsetsStart := headerSize
+	strs := make([]string, 0, stringCount)
+	for i := uint32(0); i < stringCount; i++ {
+		o := offsetsStart + int(i)*4
+		if o+4 > len(chunk) {
+			return nil, fmt.Errorf("corrupt string pool offsets")
+		}
+		strOff := int(stringsStart) + int(binary.LittleEndian.Uint32(chunk[o:]))
+		if strOff >= len(chunk) {
+			return nil, fmt.Errorf("corrupt string pool entry")
+		}
+		var s string
+		if isUTF8 {
+			s = readUTF8String(chunk[strOff:])
+		} else {
+			s = readUTF16String(chunk[strOff:])
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+func readUTF16String(b []byte) string {
+	// Two length bytes (u16 chars), followed by the UTF-16LE payload, then a
+	// trailing NUL code unit.
+	length := int(binary.LittleEndian.Uint16(b))
+	b = b[2:]
+	units := make([]uint16, 0, length)
+	for i := 0; i < length && i*2+2 <= len(b); i++ {
+		units = append(units, binary.LittleEndian.Uint16(b[i*2:]))
+	}
+	return string(utf16.Decode(units))
+}
+
+func readUTF8String(b []byte) string {
+	// utf-16 length, then utf-8 length, then the utf-8 bytes.
+	_, n := decodeUTF8Len(b)
+	b = b[n:]
+	length, n := decodeUTF8Len(b)
+	b = b[n:]
+	if length > len(b) {
+		length = len(b)
+	}
+	return string(b[:length])
+}
+
+// decodeUTF8Len reads the varint-ish length prefix used for UTF-8 string
+// pool entries, returning the decoded value and the number of bytes read.
+func decodeUTF8Len(b []byte) (int, int) {
+	if len(b) == 0 {
+		return 0, 0
+	}
+	if b[0]&0x80 == 0 {
+		return int(b[0]), 1
+	}
+	if len(b) < 2 {
+		return 0, 1
+	}
+	return int(b[0]&0x7f)<<8 | int(b[1]), 2
+}
+
+func decodeResourceMap(b []byte) []uint32 {
+	ids := make([]uint32, 0, len(b)/4)
+	for i := 0; i+4 <= len(b); i += 4 {
+		ids = append(ids, binary.LittleEndian.Uint32(b[i:]))
+	}
+	return ids
+}
+
+type attribute struct {
+	namespace int32
+	name      int32
+	rawValue  int32
+	valueType uint8
+	valueData uint32
+}
+
+// decodeStartElement parses the body of a START_TAG chunk (after the common
+// chunk header) and returns the element name and its attributes.
+func decodeStartElement(b []byte, pool []string, resourceMap []uint32) (string, []attribute) {
+	// b is the ResXMLTree_attrExt struct (the chunk header's lineNumber and
+	// comment fields have already been skipped via headerSize): nsIdx(4)
+	// nameIdx(4) attrStart(2) attrSize(2) attrCount(2) idIdx(2) classIdx(2)
+	// styleIdx(2).
+	if len(b) < 20 {
+		return "", nil
+	}
+	nameIdx := int32(binary.LittleEndian.Uint32(b[4:]))
+	attrStart := binary.LittleEndian.Uint16(b[8:])
+	attrSize := binary.LittleEndian.Uint16(b[10:])
+	attrCount := binary.LittleEndian.Uint16(b[12:])
+
+	name := resolveString(nameIdx, pool)
+	attrs := make([]attribute, 0, attrCount)
+	off := int(attrStart)
+	for i := 0; i < int(attrCount); i++ {
+		if off+int(attrSize) > len(b) {
+			break
+		}
+		a := attribute{
+			namespace: int32(binary.LittleEndian.Uint32(b[off:])),
+			name:      int32(binary.LittleEndian.Uint32(b[off+4:])),
+			rawValue:  int32(binary.LittleEndian.Uint32(b[off+8:])),
+			valueType: b[off+15],
+			valueData: binary.LittleEndian.Uint32(b[off+16:]),
+		}
+		attrs = append(attrs, a)
+		off += int(attrSize)
+	}
+	return name, attrs
+}
+
+func resolveString(idx int32, pool []string) string {
+	if idx < 0 || int(idx) >= len(pool) {
+		return ""
+	}
+	return pool[idx]
+}
+
+func manifestFromAttrs(attrs []attribute, pool []string, resourceMap []uint32) Manifest {
+	var m Manifest
+	for _, a := range attrs {
+		switch resourceIDOf(a, resourceMap) {
+		case attrVersionCode:
+			m.VersionCode = int64(a.valueData)
+		case attrVersionName:
+			m.VersionName = resolveString(a.rawValue, pool)
+		case 0:
+			// No namespaced resource ID: this is a plain attribute such as
+			// the unprefixed "package" on the root <manifest> element.
+			if resolveString(a.name, pool) == "package" {
+				m.Package = resolveString(a.rawValue, pool)
+			}
+		}
+	}
+	return m
+}
+
+// resourceIDOf resolves the android.R.attr resource ID of a namespaced
+// attribute (e.g. android:versionCode), whose name is stored as an index
+// into the resource map rather than as a literal string, or 0 for
+// unnamespaced attributes like "package".
+func resourceIDOf(a attribute, resourceMap []uint32) uint32 {
+	if a.namespace < 0 || int(a.name) >= len(resourceMap) {
+		return 0
+	}
+	return resourceMap[a.name]
+}
@@ -0,0 +1,148 @@
+package axml
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// The helpers below build just enough of a binary AndroidManifest.xml (a
+// string pool chunk, a resource map chunk and a single START_TAG chunk for
+// the root <manifest> element) to exercise Decode without a real APK.
+
+func u32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+func u16(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, v)
+	return b
+}
+
+func buildStringPoolUTF8(strings []string) []byte {
+	const headerSize = 28
+	offsets := make([]byte, 0, 4*len(strings))
+	var data []byte
+	for _, s := range strings {
+		offsets = append(offsets, u32(uint32(len(data)))...)
+		data = append(data, byte(len(s)), byte(len(s)))
+		data = append(data, s...)
+		data = append(data, 0)
+	}
+
+	body := append([]byte{}, offsets...)
+	body = append(body, data...)
+	chunkSize := headerSize + len(body)
+
+	chunk := append([]byte{}, u16(chunkStringPool)...)
+	chunk = append(chunk, u16(headerSize)...)
+	chunk = append(chunk, u32(uint32(chunkSize))...)
+	chunk = append(chunk, u32(uint32(len(strings)))...) // stringCount
+	chunk = append(chunk, u32(0)...)                    // styleCount
+	chunk = append(chunk, u32(uint32(stringPoolFlagUTF8))...)
+	chunk = append(chunk, u32(uint32(headerSize+len(offsets)))...) // stringsStart
+	chunk = append(chunk, u32(0)...)                               // stylesStart
+	chunk = append(chunk, body...)
+	return chunk
+}
+
+func buildResourceMap(ids []uint32) []byte {
+	var body []byte
+	for _, id := range ids {
+		body = append(body, u32(id)...)
+	}
+	chunkSize := 8 + len(body)
+	chunk := append([]byte{}, u16(chunkResourceMap)...)
+	chunk = append(chunk, u16(8)...)
+	chunk = append(chunk, u32(uint32(chunkSize))...)
+	return append(chunk, body...)
+}
+
+type testAttr struct {
+	namespace int32
+	name      int32
+	rawValue  int32
+	valueType uint8
+	valueData uint32
+}
+
+func buildStartElement(nameIdx int32, attrs []testAttr) []byte {
+	const headerSize = 16                           // common chunk header(8) + lineNumber(4) + comment(4)
+	body := append([]byte{}, u32(0)...)             // lineNumber
+	body = append(body, u32(0xFFFFFFFF)...)         // comment
+	body = append(body, u32(0xFFFFFFFF)...)         // namespace
+	body = append(body, u32(uint32(nameIdx))...)    // name
+	body = append(body, u16(20)...)                 // attrStart (relative to the attrExt struct)
+	body = append(body, u16(20)...)                 // attrSize
+	body = append(body, u16(uint16(len(attrs)))...) // attrCount
+	body = append(body, u16(0)...)                  // idIdx
+	body = append(body, u16(0)...)                  // classIdx
+	body = append(body, u16(0)...)                  // styleIdx
+
+	for _, a := range attrs {
+		entry := append([]byte{}, u32(uint32(a.namespace))...)
+		entry = append(entry, u32(uint32(a.name))...)
+		entry = append(entry, u32(uint32(a.rawValue))...)
+		entry = append(entry, u16(8)...) // typedValue.size
+		entry = append(entry, 0)         // typedValue.res0
+		entry = append(entry, a.valueType)
+		entry = append(entry, u32(a.valueData)...)
+		body = append(body, entry...)
+	}
+
+	chunkSize := 8 + len(body)
+	chunk := append([]byte{}, u16(chunkStartElement)...)
+	chunk = append(chunk, u16(headerSize)...)
+	chunk = append(chunk, u32(uint32(chunkSize))...)
+	return append(chunk, body...)
+}
+
+func wrapXML(chunks ...[]byte) []byte {
+	var body []byte
+	for _, c := range chunks {
+		body = append(body, c...)
+	}
+	chunkSize := 8 + len(body)
+	xml := append([]byte{}, u16(0x0003)...) // RES_XML_TYPE
+	xml = append(xml, u16(8)...)
+	xml = append(xml, u32(uint32(chunkSize))...)
+	return append(xml, body...)
+}
+
+func Test_Decode(t *testing.T) {
+	pool := buildStringPoolUTF8([]string{
+		"manifest",    // 0
+		"package",     // 1
+		"com.foo.bar", // 2
+		"versionCode", // 3
+		"",            // 4 (unused raw value slot)
+		"versionName", // 5
+		"1.2.3",       // 6
+	})
+	resourceMap := buildResourceMap([]uint32{0, 0, 0, attrVersionCode, 0, attrVersionName, 0})
+	startElement := buildStartElement(0, []testAttr{
+		{namespace: -1, name: 1, rawValue: 2, valueType: 3, valueData: 2},
+		{namespace: 0, name: 3, rawValue: -1, valueType: 0x10, valueData: 42},
+		{namespace: 0, name: 5, rawValue: 6, valueType: 3, valueData: 6},
+	})
+
+	data := wrapXML(pool, resourceMap, startElement)
+
+	m, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := Manifest{Package: "com.foo.bar", VersionCode: 42, VersionName: "1.2.3"}
+	if m != want {
+		t.Errorf("Decode() = %+v, want %+v", m, want)
+	}
+}
+
+func Test_Decode_notBinaryXML(t *testing.T) {
+	if _, err := Decode([]byte("not xml")); err == nil {
+		t.Error("Decode() expected error for non-AXML input")
+	}
+}
@@ -0,0 +1,116 @@
+// Package bundlemanifest decodes the protobuf-serialized
+// base/manifest/AndroidManifest.xml stored inside an Android App Bundle,
+// reading just enough of aapt2's XmlNode wire format to recover the
+// package name and version metadata.
+package bundlemanifest
+
+import (
+	"fmt"
+)
+
+// Manifest holds the subset of AndroidManifest.xml fields this step validates.
+type Manifest struct {
+	Package     string
+	VersionCode int64
+	VersionName string
+}
+
+// field numbers from aapt2's Resources.proto XmlNode/XmlElementNode/XmlAttribute messages.
+const (
+	fieldNodeElement = 1
+
+	fieldElementName      = 3
+	fieldElementAttribute = 4
+
+	fieldAttributeNamespaceURI = 1
+	fieldAttributeName         = 2
+	fieldAttributeValue        = 3
+	fieldAttributeCompiledItem = 4
+
+	androidNamespaceURI = "http://schemas.android.com/apk/res/android"
+)
+
+// Decode parses a protobuf-encoded XmlNode message (the root of
+// base/manifest/AndroidManifest.xml in an .aab) and returns the package name
+// and version fields declared on the root <manifest> element.
+func Decode(data []byte) (Manifest, error) {
+	fields, err := parseMessage(data)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse bundle manifest: %w", err)
+	}
+
+	element, ok := findLengthDelimited(fields, fieldNodeElement)
+	if !ok {
+		return Manifest{}, fmt.Errorf("<manifest> element not found in base/manifest/AndroidManifest.xml")
+	}
+
+	elementFields, err := parseMessage(element)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse <manifest> element: %w", err)
+	}
+
+	var m Manifest
+	for _, f := range elementFields {
+		if f.number != fieldElementAttribute || f.wireType != wireLengthDelimited {
+			continue
+		}
+		attr, err := parseMessage(f.bytes)
+		if err != nil {
+			continue
+		}
+		name, _ := stringField(attr, fieldAttributeName)
+		value, _ := stringField(attr, fieldAttributeValue)
+		namespace, _ := stringField(attr, fieldAttributeNamespaceURI)
+
+		switch {
+		case namespace == "" && name == "package":
+			m.Package = value
+		case namespace == androidNamespaceURI && name == "versionCode":
+			m.VersionCode = compiledVersionCode(attr, value)
+		case namespace == androidNamespaceURI && name == "versionName":
+			m.VersionName = value
+		}
+	}
+
+	return m, nil
+}
+
+// compiledVersionCode reads an XmlAttribute's versionCode, preferring the
+// compiled_item primitive int aapt2 serializes for a bundle manifest (value
+// is left empty in that case) and falling back to the APK-style string value.
+func compiledVersionCode(attr []protoField, value string) int64 {
+	for _, f := range attr {
+		if f.number == fieldAttributeCompiledItem && f.wireType == wireVarint {
+			return int64(f.varint)
+		}
+	}
+	return parseVersionCode(value)
+}
+
+func parseVersionCode(s string) int64 {
+	var v int64
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0
+		}
+		v = v*10 + int64(r-'0')
+	}
+	return v
+}
+
+func findLengthDelimited(fields []protoField, number int) ([]byte, bool) {
+	for _, f := range fields {
+		if f.number == number && f.wireType == wireLengthDelimited {
+			return f.bytes, true
+		}
+	}
+	return nil, false
+}
+
+func stringField(fields []protoField, number int) (string, bool) {
+	b, ok := findLengthDelimited(fields, number)
+	if !ok {
+		return "", false
+	}
+	return string(b), true
+}
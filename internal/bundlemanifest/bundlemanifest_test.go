@@ -0,0 +1,105 @@
+package bundlemanifest
+
+import "testing"
+
+// encodeVarint appends v to b as a protobuf varint.
+func encodeVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func encodeTag(b []byte, number, wireType int) []byte {
+	return encodeVarint(b, uint64(number)<<3|uint64(wireType))
+}
+
+func encodeString(b []byte, number int, s string) []byte {
+	b = encodeTag(b, number, wireLengthDelimited)
+	b = encodeVarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func encodeAttribute(namespace, name, value string) []byte {
+	var b []byte
+	if namespace != "" {
+		b = encodeString(b, fieldAttributeNamespaceURI, namespace)
+	}
+	b = encodeString(b, fieldAttributeName, name)
+	b = encodeString(b, fieldAttributeValue, value)
+	return b
+}
+
+// encodeCompiledAttribute builds an XmlAttribute the way aapt2 serializes a
+// bundle manifest: value is left empty and the resolved int is carried in
+// compiled_item instead.
+func encodeCompiledAttribute(namespace, name string, compiledItem int64) []byte {
+	var b []byte
+	if namespace != "" {
+		b = encodeString(b, fieldAttributeNamespaceURI, namespace)
+	}
+	b = encodeString(b, fieldAttributeName, name)
+	b = encodeTag(b, fieldAttributeCompiledItem, wireVarint)
+	b = encodeVarint(b, uint64(compiledItem))
+	return b
+}
+
+func encodeElement(attrs ...[]byte) []byte {
+	var b []byte
+	for _, a := range attrs {
+		b = encodeTag(b, fieldElementAttribute, wireLengthDelimited)
+		b = encodeVarint(b, uint64(len(a)))
+		b = append(b, a...)
+	}
+	return b
+}
+
+func encodeNode(element []byte) []byte {
+	var b []byte
+	b = encodeTag(b, fieldNodeElement, wireLengthDelimited)
+	b = encodeVarint(b, uint64(len(element)))
+	return append(b, element...)
+}
+
+func Test_Decode(t *testing.T) {
+	element := encodeElement(
+		encodeAttribute("", "package", "com.foo.bar"),
+		encodeAttribute(androidNamespaceURI, "versionCode", "42"),
+		encodeAttribute(androidNamespaceURI, "versionName", "1.2.3"),
+	)
+
+	m, err := Decode(encodeNode(element))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := Manifest{Package: "com.foo.bar", VersionCode: 42, VersionName: "1.2.3"}
+	if m != want {
+		t.Errorf("Decode() = %+v, want %+v", m, want)
+	}
+}
+
+func Test_Decode_compiledVersionCode(t *testing.T) {
+	element := encodeElement(
+		encodeAttribute("", "package", "com.foo.bar"),
+		encodeCompiledAttribute(androidNamespaceURI, "versionCode", 42),
+		encodeAttribute(androidNamespaceURI, "versionName", "1.2.3"),
+	)
+
+	m, err := Decode(encodeNode(element))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := Manifest{Package: "com.foo.bar", VersionCode: 42, VersionName: "1.2.3"}
+	if m != want {
+		t.Errorf("Decode() = %+v, want %+v", m, want)
+	}
+}
+
+func Test_Decode_missingElement(t *testing.T) {
+	if _, err := Decode(nil); err == nil {
+		t.Error("Decode() expected error for a manifest with no <manifest> element")
+	}
+}
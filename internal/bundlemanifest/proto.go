@@ -0,0 +1,82 @@
+package bundlemanifest
+
+import "fmt"
+
+const (
+	wireVarint          = 0
+	wireFixed64         = 1
+	wireLengthDelimited = 2
+	wireFixed32         = 5
+)
+
+// protoField is one decoded field of an unknown protobuf message: enough of
+// the wire format to pick out the length-delimited sub-messages and strings
+// this package needs, without depending on the full Resources.proto schema.
+type protoField struct {
+	number   int
+	wireType int
+	varint   uint64
+	bytes    []byte
+}
+
+// parseMessage splits a protobuf-encoded message into its top-level fields.
+func parseMessage(data []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(data) > 0 {
+		tag, n := readVarint(data)
+		if n == 0 {
+			return nil, fmt.Errorf("truncated field tag")
+		}
+		data = data[n:]
+
+		number := int(tag >> 3)
+		wireType := int(tag & 0x7)
+
+		switch wireType {
+		case wireVarint:
+			v, n := readVarint(data)
+			if n == 0 {
+				return nil, fmt.Errorf("truncated varint field")
+			}
+			data = data[n:]
+			fields = append(fields, protoField{number: number, wireType: wireType, varint: v})
+		case wireFixed64:
+			if len(data) < 8 {
+				return nil, fmt.Errorf("truncated fixed64 field")
+			}
+			fields = append(fields, protoField{number: number, wireType: wireType, bytes: data[:8]})
+			data = data[8:]
+		case wireLengthDelimited:
+			length, n := readVarint(data)
+			if n == 0 || uint64(len(data)-n) < length {
+				return nil, fmt.Errorf("truncated length-delimited field")
+			}
+			data = data[n:]
+			fields = append(fields, protoField{number: number, wireType: wireType, bytes: data[:length]})
+			data = data[length:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return nil, fmt.Errorf("truncated fixed32 field")
+			}
+			fields = append(fields, protoField{number: number, wireType: wireType, bytes: data[:4]})
+			data = data[4:]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d", wireType)
+		}
+	}
+	return fields, nil
+}
+
+// readVarint reads a base-128 varint and returns its value and the number of
+// bytes consumed, or 0 bytes consumed if data is truncated.
+func readVarint(data []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		v |= uint64(b&0x7f) << (7 * uint(i))
+		if b&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
@@ -0,0 +1,52 @@
+// Package strdist provides small string-distance helpers used to suggest
+// corrections for likely user typos (e.g. a misspelled track name).
+package strdist
+
+// Levenshtein returns the edit distance between a and b: the minimum number
+// of single-character insertions, deletions or substitutions required to
+// turn a into b. A transposition of two adjacent characters counts as 2
+// (one deletion and one insertion), matching the classic definition.
+//
+// It runs in O(len(a)*len(b)) time and O(min(len(a),len(b))) space, keeping
+// only the previous row of the DP table.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	curr := make([]int, len(rb)+1)
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(
+				prev[j]+1,      // deletion
+				curr[j-1]+1,    // insertion
+				prev[j-1]+cost, // substitution (or match)
+			)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
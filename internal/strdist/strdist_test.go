@@ -0,0 +1,31 @@
+package strdist
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		{name: "equal strings", a: "production", b: "production", want: 0},
+		{name: "empty vs empty", a: "", b: "", want: 0},
+		{name: "empty vs non-empty is all insertions", a: "", b: "beta", want: 4},
+		{name: "insertion", a: "prod", b: "produ", want: 1},
+		{name: "deletion", a: "produ", b: "prod", want: 1},
+		{name: "substitution", a: "prouction", b: "production", want: 1},
+		{name: "transposition counts as 2", a: "prodcution", b: "production", want: 2},
+		{name: "completely different", a: "internal", b: "production", want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := Levenshtein(tt.b, tt.a); got != tt.want {
+				t.Errorf("Levenshtein(%q, %q) = %d, want %d (symmetry)", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/bitrise-io/go-steputils/stepconf"
+	"github.com/bitrise-io/go-utils/log"
+)
+
+func failf(format string, args ...interface{}) {
+	log.Errorf(format, args...)
+	os.Exit(1)
+}
+
+func main() {
+	var config Configs
+	if err := stepconf.Parse(&config); err != nil {
+		failf("Couldn't create step config: %s", err)
+	}
+	stepconf.Print(config)
+
+	if err := config.validate(); err != nil {
+		failf("Input validation failed: %s", err)
+	}
+
+	ctx := context.Background()
+
+	service, err := newPublisherService(ctx, config)
+	if err != nil {
+		failf("Failed to create Google Play API client: %s", err)
+	}
+
+	edit, err := service.Edits.Insert(config.PackageName, nil).Do()
+	if err != nil {
+		failf("Failed to create a new Edit: %s", err)
+	}
+	editID := edit.Id
+
+	trackNames, err := editTrackNames(service, config.PackageName, editID)
+	if err != nil {
+		failf("%s", err)
+	}
+
+	track, err := config.resolveTrack(trackNames)
+	if err != nil {
+		failf("%s", err)
+	}
+
+	switch config.action() {
+	case actionUpload:
+		err = upload(service, config, editID, track)
+	case actionHalt:
+		err = halt(service, config.PackageName, editID, track)
+	case actionResume:
+		err = resume(service, config.PackageName, editID, track)
+	case actionUpdateRollout:
+		err = updateRollout(service, config, editID, track)
+	case actionPromote:
+		err = promote(service, config, editID, track)
+	}
+	if err != nil {
+		failf("%s", err)
+	}
+
+	if _, err := service.Edits.Commit(config.PackageName, editID).Do(); err != nil {
+		failf("Failed to commit edit: %s", err)
+	}
+
+	log.Donef("Success")
+}
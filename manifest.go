@@ -0,0 +1,130 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/log"
+
+	"github.com/wezach/steps-google-play-deploy/internal/axml"
+	"github.com/wezach/steps-google-play-deploy/internal/bundlemanifest"
+)
+
+const (
+	apkManifestEntry = "AndroidManifest.xml"
+	aabManifestEntry = "base/manifest/AndroidManifest.xml"
+)
+
+// appManifest is the subset of AndroidManifest.xml fields read out of an
+// app artifact before it is uploaded to Google Play.
+type appManifest struct {
+	Package     string
+	VersionCode int64
+	VersionName string
+}
+
+// readAppManifest extracts the package name and version fields from an .apk
+// or .aab at pth, by decoding its (binary or protobuf encoded)
+// AndroidManifest.xml without shelling out to aapt.
+func readAppManifest(pth string) (appManifest, error) {
+	ext := strings.ToLower(filepath.Ext(pth))
+
+	var entryName string
+	switch ext {
+	case ".apk":
+		entryName = apkManifestEntry
+	case ".aab":
+		entryName = aabManifestEntry
+	default:
+		return appManifest{}, fmt.Errorf("unsupported app artifact extension: %s", ext)
+	}
+
+	data, err := readZipEntry(pth, entryName)
+	if err != nil {
+		return appManifest{}, err
+	}
+
+	if ext == ".apk" {
+		m, err := axml.Decode(data)
+		if err != nil {
+			return appManifest{}, fmt.Errorf("failed to decode manifest of %s: %w", pth, err)
+		}
+		return appManifest(m), nil
+	}
+
+	m, err := bundlemanifest.Decode(data)
+	if err != nil {
+		return appManifest{}, fmt.Errorf("failed to decode manifest of %s: %w", pth, err)
+	}
+	return appManifest(m), nil
+}
+
+func readZipEntry(pth, entryName string) ([]byte, error) {
+	r, err := zip.OpenReader(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s as a zip archive: %w", pth, err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name != entryName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from %s: %w", entryName, pth, err)
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+
+	return nil, fmt.Errorf("%s not found in %s", entryName, pth)
+}
+
+// validateAppManifests reads the manifest of every app artifact, checking
+// that each declares the configured package name, before any Edit is
+// created on Google Play. It returns the detected versionCodes (one per
+// successfully decoded app, in apps order) for logging and for use when
+// composing the release.
+//
+// Google Play's Multiple-APK support requires every APK in a release to
+// carry a distinct versionCode (that's how it tells ABI/density splits
+// apart), so versionCodes are checked for duplicates rather than required
+// to match.
+//
+// A manifest the parser can't decode only produces a warning, not a fatal
+// error: internal/axml and internal/bundlemanifest read just enough of the
+// binary-XML/protobuf formats for the common case, and a manifest variation
+// they mishandle shouldn't turn a previously-working deploy into a blocked
+// one. A package name that *is* successfully decoded but mismatches is
+// still fatal, since that's the actual misconfiguration this check exists
+// to catch.
+func (c Configs) validateAppManifests(apps []string) ([]int64, error) {
+	versionCodes := make([]int64, 0, len(apps))
+	seenAt := make(map[int64]string, len(apps))
+	for _, pth := range apps {
+		manifest, err := readAppManifest(pth)
+		if err != nil {
+			log.Warnf("failed to pre-validate %s, uploading without a package/versionCode check: %s", pth, err)
+			continue
+		}
+
+		if manifest.Package != c.PackageName {
+			return nil, fmt.Errorf("app_path package %s does not match configured package_name %s (%s)", manifest.Package, c.PackageName, pth)
+		}
+
+		log.Infof("%s: package %s, versionCode %d, versionName %s", pth, manifest.Package, manifest.VersionCode, manifest.VersionName)
+
+		if other, ok := seenAt[manifest.VersionCode]; ok {
+			return nil, fmt.Errorf("duplicate versionCode %d across app artifacts: %s and %s", manifest.VersionCode, other, pth)
+		}
+		seenAt[manifest.VersionCode] = pth
+
+		versionCodes = append(versionCodes, manifest.VersionCode)
+	}
+
+	return versionCodes, nil
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/log"
+	"golang.org/x/oauth2/google"
+	androidpublisher "google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/option"
+)
+
+// newPublisherService creates an authenticated Google Play Developer API
+// client from service_account_json_key_path, which is either a file:// path
+// to a service account JSON key or the key's raw JSON content.
+func newPublisherService(ctx context.Context, c Configs) (*androidpublisher.Service, error) {
+	key := string(c.JSONKeyPath)
+	if strings.HasPrefix(key, "file://") {
+		data, err := os.ReadFile(strings.TrimPrefix(key, "file://"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read service account json key: %w", err)
+		}
+		key = string(data)
+	}
+
+	jwtConf, err := google.JWTConfigFromJSON([]byte(key), androidpublisher.AndroidpublisherScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service account json key: %w", err)
+	}
+
+	service, err := androidpublisher.NewService(ctx, option.WithHTTPClient(jwtConf.Client(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create androidpublisher client: %w", err)
+	}
+	return service, nil
+}
+
+// editTrackNames lists the tracks already on the Edit, for Configs.resolveTrack
+// to match the configured track name against.
+func editTrackNames(service *androidpublisher.Service, packageName, editID string) ([]string, error) {
+	tracks, err := service.Edits.Tracks.List(packageName, editID).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracks: %w", err)
+	}
+
+	names := make([]string, 0, len(tracks.Tracks))
+	for _, t := range tracks.Tracks {
+		names = append(names, t.Track)
+	}
+	return names, nil
+}
+
+// editListingLocales lists the locales the app's store listing is configured
+// for, which is the set of "target track locales" whatsnewsForLocales
+// resolves release notes (with fallback substitution) for.
+func editListingLocales(service *androidpublisher.Service, packageName, editID string) ([]string, error) {
+	listings, err := service.Edits.Listings.List(packageName, editID).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list store listing locales: %w", err)
+	}
+
+	locales := make([]string, 0, len(listings.Listings))
+	for _, l := range listings.Listings {
+		locales = append(locales, l.Language)
+	}
+	return locales, nil
+}
+
+// releaseNotesFor resolves the release notes from whatsnews_dir (with
+// whatsnews_fallback_locale substitution) into the LocalizedText entries
+// Edits.Tracks.Update expects.
+func releaseNotesFor(c Configs, locales []string) ([]*androidpublisher.LocalizedText, error) {
+	if c.WhatsnewsDir == "" {
+		return nil, nil
+	}
+
+	notes, err := c.whatsnewsForLocales(locales)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := make([]*androidpublisher.LocalizedText, 0, len(notes))
+	for locale, text := range notes {
+		texts = append(texts, &androidpublisher.LocalizedText{Language: locale, Text: text})
+	}
+	sort.Slice(texts, func(i, j int) bool { return texts[i].Language < texts[j].Language })
+	return texts, nil
+}
+
+// uploadArtifacts uploads every artifact in release to editID and returns
+// the versionCode Google Play assigned each (one per release.Paths, in
+// order), for use when composing the track's release.
+func uploadArtifacts(service *androidpublisher.Service, packageName, editID string, release appRelease) ([]int64, error) {
+	versionCodes := make([]int64, 0, len(release.Paths))
+	for _, pth := range release.Paths {
+		vc, err := uploadArtifact(service, packageName, editID, pth)
+		if err != nil {
+			return nil, err
+		}
+		log.Donef("Uploaded %s as versionCode %d", pth, vc)
+		versionCodes = append(versionCodes, vc)
+	}
+	return versionCodes, nil
+}
+
+func uploadArtifact(service *androidpublisher.Service, packageName, editID, pth string) (int64, error) {
+	f, err := os.Open(pth)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", pth, err)
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(pth), ".aab") {
+		bundle, err := service.Edits.Bundles.Upload(packageName, editID).Media(f).Do()
+		if err != nil {
+			return 0, fmt.Errorf("failed to upload %s: %w", pth, err)
+		}
+		return bundle.VersionCode, nil
+	}
+
+	apk, err := service.Edits.Apks.Upload(packageName, editID).Media(f).Do()
+	if err != nil {
+		return 0, fmt.Errorf("failed to upload %s: %w", pth, err)
+	}
+	return apk.VersionCode, nil
+}
+
+// upload performs the `upload` action: it uploads every artifact resolved by
+// Configs.resolveAppRelease and puts them, as a single release, on track.
+func upload(service *androidpublisher.Service, c Configs, editID, track string) error {
+	release, err := c.resolveAppRelease()
+	if err != nil {
+		return err
+	}
+
+	versionCodes, err := uploadArtifacts(service, c.PackageName, editID, release)
+	if err != nil {
+		return err
+	}
+
+	locales, err := editListingLocales(service, c.PackageName, editID)
+	if err != nil {
+		return err
+	}
+	releaseNotes, err := releaseNotesFor(c, locales)
+	if err != nil {
+		return err
+	}
+
+	trackRelease := &androidpublisher.TrackRelease{
+		Status:              "completed",
+		VersionCodes:        versionCodes,
+		ReleaseNotes:        releaseNotes,
+		InAppUpdatePriority: int64(c.UpdatePriority),
+	}
+	if c.UserFraction > 0 {
+		trackRelease.Status = "inProgress"
+		trackRelease.UserFraction = c.UserFraction
+	}
+
+	return updateTrack(service, c.PackageName, editID, track, trackRelease)
+}
+
+// currentRelease returns the first in-progress or halted release (the one a
+// halt/resume/update_rollout action operates on) of track, or an error if
+// track has none.
+func currentRelease(service *androidpublisher.Service, packageName, editID, track string) (*androidpublisher.Track, *androidpublisher.TrackRelease, error) {
+	t, err := service.Edits.Tracks.Get(packageName, editID, track).Do()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get track %s: %w", track, err)
+	}
+
+	for _, release := range t.Releases {
+		if release.Status == "inProgress" || release.Status == "halted" {
+			return t, release, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("track %s has no in-progress or halted release to act on", track)
+}
+
+// updateTrack writes release back onto track on the Edit.
+func updateTrack(service *androidpublisher.Service, packageName, editID, track string, release *androidpublisher.TrackRelease) error {
+	t := &androidpublisher.Track{Track: track, Releases: []*androidpublisher.TrackRelease{release}}
+	if _, err := service.Edits.Tracks.Update(packageName, editID, track, t).Do(); err != nil {
+		return fmt.Errorf("failed to update track %s: %w", track, err)
+	}
+	return nil
+}
+
+// halt performs the `halt` action, pausing track's staged rollout.
+func halt(service *androidpublisher.Service, packageName, editID, track string) error {
+	_, release, err := currentRelease(service, packageName, editID, track)
+	if err != nil {
+		return err
+	}
+	release.Status = "halted"
+	return updateTrack(service, packageName, editID, track, release)
+}
+
+// resume performs the `resume` action, continuing track's staged rollout.
+func resume(service *androidpublisher.Service, packageName, editID, track string) error {
+	_, release, err := currentRelease(service, packageName, editID, track)
+	if err != nil {
+		return err
+	}
+	release.Status = "inProgress"
+	return updateTrack(service, packageName, editID, track, release)
+}
+
+// updateRollout performs the `update_rollout` action, adjusting the
+// userFraction/inAppUpdatePriority of track's in-progress release without
+// touching its artifacts.
+func updateRollout(service *androidpublisher.Service, c Configs, editID, track string) error {
+	_, release, err := currentRelease(service, c.PackageName, editID, track)
+	if err != nil {
+		return err
+	}
+	if c.UserFraction > 0 {
+		release.UserFraction = c.UserFraction
+	}
+	release.InAppUpdatePriority = int64(c.UpdatePriority)
+	return updateTrack(service, c.PackageName, editID, track, release)
+}
+
+// promote performs the `promote` action, moving the versionCodes currently
+// on SourceTrack onto track at UserFraction, without re-uploading bytes.
+func promote(service *androidpublisher.Service, c Configs, editID, track string) error {
+	t, err := service.Edits.Tracks.Get(c.PackageName, editID, c.SourceTrack).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get track %s: %w", c.SourceTrack, err)
+	}
+
+	// Prefer a staged (in-progress) release, else the completed one.
+	var sourceRelease *androidpublisher.TrackRelease
+	for _, r := range t.Releases {
+		if r.Status == "inProgress" || (r.Status == "completed" && sourceRelease == nil) {
+			sourceRelease = r
+		}
+	}
+	if sourceRelease == nil {
+		return fmt.Errorf("track %s has no completed or in-progress release to promote", c.SourceTrack)
+	}
+
+	release := &androidpublisher.TrackRelease{
+		Status:       "completed",
+		VersionCodes: sourceRelease.VersionCodes,
+		ReleaseNotes: sourceRelease.ReleaseNotes,
+	}
+	if c.UserFraction > 0 {
+		release.Status = "inProgress"
+		release.UserFraction = c.UserFraction
+	}
+
+	return updateTrack(service, c.PackageName, editID, track, release)
+}
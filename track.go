@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/bitrise-io/go-utils/log"
+
+	"github.com/wezach/steps-google-play-deploy/internal/strdist"
+)
+
+// trackDistanceThreshold returns how many edits away from name a track is
+// still considered a likely typo of it, rather than an unrelated track.
+func trackDistanceThreshold(name string) int {
+	return int(math.Ceil(float64(len([]rune(name))) / 3))
+}
+
+const maxAutoCorrectDistance = 2
+
+// resolveTrack matches Configs.Track against the tracks available on the
+// app's Edit (as listed via Edits.Tracks.List), to catch a misspelled track
+// name before Google Play's terse 404 does. An exact match is returned
+// as-is. Otherwise, if exactly one available track is within the fixed or
+// the name-relative typo distance (whichever is larger), it is treated as the
+// intended track:
+// the step either auto-corrects with a warning, or, when strict_track is
+// set, fails asking the user to confirm the correction.
+func (c Configs) resolveTrack(available []string) (string, error) {
+	for _, track := range available {
+		if track == c.Track {
+			return track, nil
+		}
+	}
+
+	threshold := maxAutoCorrectDistance
+	if relative := trackDistanceThreshold(c.Track); relative > threshold {
+		threshold = relative
+	}
+
+	var candidates []string
+	for _, track := range available {
+		if strdist.Levenshtein(c.Track, track) <= threshold {
+			candidates = append(candidates, track)
+		}
+	}
+
+	if len(candidates) != 1 {
+		return "", fmt.Errorf("unknown track '%s', available tracks: %v", c.Track, available)
+	}
+
+	suggestion := candidates[0]
+	if c.StrictTrack {
+		return "", fmt.Errorf("unknown track '%s'; did you mean '%s'?", c.Track, suggestion)
+	}
+
+	log.Warnf("unknown track '%s', using closest match '%s' (set strict_track to fail instead)", c.Track, suggestion)
+	return suggestion, nil
+}
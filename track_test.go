@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestConfigs_resolveTrack(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Configs
+		available []string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "exact match",
+			config:    Configs{Track: "production"},
+			available: []string{"internal", "beta", "production"},
+			want:      "production",
+		},
+		{
+			name:      "single typo auto-corrects",
+			config:    Configs{Track: "prouction"},
+			available: []string{"internal", "beta", "production"},
+			want:      "production",
+		},
+		{
+			name:      "single typo fails in strict mode",
+			config:    Configs{Track: "prouction", StrictTrack: true},
+			available: []string{"internal", "beta", "production"},
+			wantErr:   true,
+		},
+		{
+			name:      "no close candidate",
+			config:    Configs{Track: "qa"},
+			available: []string{"internal", "beta", "production"},
+			wantErr:   true,
+		},
+		{
+			name:      "ambiguous between two close candidates",
+			config:    Configs{Track: "beat"},
+			available: []string{"beta", "beet"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.resolveTrack(tt.available)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Configs.resolveTrack() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Configs.resolveTrack() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/log"
+)
+
+// whatsnewsMaxRunes is Google Play's release notes length limit per locale.
+const whatsnewsMaxRunes = 500
+
+// supportedListingLocales lists the BCP-47 locale codes Google Play accepts
+// for store listing text (including release notes). Not exhaustive of every
+// locale Play may add, but covers the locales it documents today.
+var supportedListingLocales = map[string]bool{
+	"af": true, "am": true, "ar": true, "az-AZ": true, "be": true, "bg": true,
+	"bn-BD": true, "ca": true, "cs-CZ": true, "da-DK": true, "de-DE": true,
+	"el-GR": true, "en-AU": true, "en-CA": true, "en-GB": true, "en-IN": true,
+	"en-SG": true, "en-US": true, "en-ZA": true, "es-419": true, "es-ES": true,
+	"es-US": true, "et": true, "eu-ES": true, "fa": true, "fi-FI": true,
+	"fil": true, "fr-CA": true, "fr-FR": true, "gl-ES": true, "hi-IN": true,
+	"hr": true, "hu-HU": true, "hy-AM": true, "id": true, "is-IS": true,
+	"it-IT": true, "iw-IL": true, "ja-JP": true, "ka-GE": true, "kk": true,
+	"km-KH": true, "kn-IN": true, "ko-KR": true, "ky-KG": true, "lo-LA": true,
+	"lt": true, "lv": true, "mk-MK": true, "ml-IN": true, "mn-MN": true,
+	"mr-IN": true, "ms": true, "ms-MY": true, "my-MM": true, "ne-NP": true,
+	"nl-NL": true, "no-NO": true, "pa": true, "pl-PL": true, "pt-BR": true,
+	"pt-PT": true, "rm": true, "ro": true, "ru-RU": true, "si-LK": true,
+	"sk": true, "sl": true, "sr": true, "sv-SE": true, "sw": true, "ta-IN": true,
+	"te-IN": true, "th": true, "tr-TR": true, "uk": true, "ur": true,
+	"vi": true, "zh-CN": true, "zh-HK": true, "zh-TW": true, "zu": true,
+}
+
+// whatsnewLocale returns the locale a whatsnews_dir file represents, i.e.
+// its name without extension, e.g. "en-US.txt" -> "en-US".
+func whatsnewLocale(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// whatsnewFile is a single file found directly inside whatsnews_dir.
+type whatsnewFile struct {
+	name    string
+	locale  string
+	content string
+}
+
+// readWhatsnewFiles reads every regular file directly inside dir, in
+// filename order.
+func readWhatsnewFiles(dir string) ([]whatsnewFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read what's new directory %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	files := make([]whatsnewFile, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		files = append(files, whatsnewFile{name: name, locale: whatsnewLocale(name), content: string(data)})
+	}
+
+	return files, nil
+}
+
+// validateWhatsnews parses whatsnews_dir and reports every offending file in
+// a single consolidated error (duplicate or unknown locale, oversize, or
+// empty release notes), instead of only surfacing the first problem Google
+// Play's API would reject.
+func (c Configs) validateWhatsnews() error {
+	files, err := readWhatsnewFiles(c.WhatsnewsDir)
+	if err != nil {
+		return err
+	}
+
+	seenLocales := map[string]string{}
+
+	var issues []string
+	for _, file := range files {
+		if other, ok := seenLocales[file.locale]; ok {
+			issues = append(issues, fmt.Sprintf("%s: locale %q is already provided by %s", file.name, file.locale, other))
+			continue
+		}
+		seenLocales[file.locale] = file.name
+
+		if !supportedListingLocales[file.locale] {
+			issue := fmt.Sprintf("%s: %q is not a locale Google Play supports for store listings", file.name, file.locale)
+			if c.WhatsnewsStrict {
+				issues = append(issues, issue)
+			} else {
+				log.Warnf("%s, it will still be uploaded as-is and Play may reject it; set whatsnews_strict to fail locally instead", issue)
+			}
+		}
+
+		if strings.TrimSpace(file.content) == "" {
+			issues = append(issues, fmt.Sprintf("%s: release notes are empty", file.name))
+			continue
+		}
+
+		if length := len([]rune(file.content)); length > whatsnewsMaxRunes {
+			issues = append(issues, fmt.Sprintf("%s: release notes are %d characters long, exceeding the %d character limit", file.name, length, whatsnewsMaxRunes))
+		}
+	}
+
+	if c.WhatsnewsFallbackLocale != "" {
+		if _, ok := seenLocales[c.WhatsnewsFallbackLocale]; !ok {
+			issues = append(issues, fmt.Sprintf("whatsnews_fallback_locale %q has no matching file in %s", c.WhatsnewsFallbackLocale, c.WhatsnewsDir))
+		}
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("invalid what's new content:\n- %s", strings.Join(issues, "\n- "))
+	}
+
+	return nil
+}
+
+// whatsnewsForLocales returns the release notes to upload for each of
+// trackLocales, reading whatsnews_dir and, for any trackLocale missing its
+// own file, substituting the contents of whatsnews_fallback_locale when one
+// is configured and present.
+func (c Configs) whatsnewsForLocales(trackLocales []string) (map[string]string, error) {
+	files, err := readWhatsnewFiles(c.WhatsnewsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make(map[string]string, len(files))
+	for _, file := range files {
+		contents[file.locale] = file.content
+	}
+
+	fallback, hasFallback := contents[c.WhatsnewsFallbackLocale]
+
+	result := make(map[string]string, len(trackLocales))
+	for _, locale := range trackLocales {
+		if note, ok := contents[locale]; ok {
+			result[locale] = note
+			continue
+		}
+
+		if !hasFallback {
+			continue
+		}
+
+		log.Infof("no what's new found for %s, falling back to %s", locale, c.WhatsnewsFallbackLocale)
+		result[locale] = fallback
+	}
+
+	return result, nil
+}
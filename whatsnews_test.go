@@ -0,0 +1,153 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeWhatsnewFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestConfigs_validateWhatsnews(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Configs
+		files       map[string]string
+		wantErr     bool
+		wantErrPart string
+	}{
+		{
+			name:   "valid notes",
+			config: Configs{},
+			files: map[string]string{
+				"en-US.txt": "Bug fixes and performance improvements.",
+				"fr-FR.txt": "Corrections de bugs.",
+			},
+			wantErr: false,
+		},
+		{
+			name:   "unknown locale dir name is only a warning by default",
+			config: Configs{},
+			files: map[string]string{
+				"en-US.txt": "Bug fixes.",
+				"xx-XX.txt": "Some notes.",
+			},
+			wantErr: false,
+		},
+		{
+			name:        "unknown locale dir name is an error in strict mode",
+			config:      Configs{WhatsnewsStrict: true},
+			files:       map[string]string{"xx-XX.txt": "Some notes."},
+			wantErr:     true,
+			wantErrPart: "not a locale Google Play supports",
+		},
+		{
+			name:        "oversize note counting multi-byte runes",
+			config:      Configs{},
+			files:       map[string]string{"ja-JP.txt": strings.Repeat("あ", 501)},
+			wantErr:     true,
+			wantErrPart: "exceeding the 500 character limit",
+		},
+		{
+			name:    "note at the limit is valid",
+			config:  Configs{},
+			files:   map[string]string{"en-US.txt": strings.Repeat("a", 500)},
+			wantErr: false,
+		},
+		{
+			name:        "empty file",
+			config:      Configs{},
+			files:       map[string]string{"en-US.txt": "   \n"},
+			wantErr:     true,
+			wantErrPart: "release notes are empty",
+		},
+		{
+			name:    "configured fallback locale with a matching file",
+			config:  Configs{WhatsnewsFallbackLocale: "en-US"},
+			files:   map[string]string{"en-US.txt": "English notes."},
+			wantErr: false,
+		},
+		{
+			name:        "configured fallback locale with no matching file",
+			config:      Configs{WhatsnewsFallbackLocale: "en-US"},
+			files:       map[string]string{"fr-FR.txt": "Notes en français."},
+			wantErr:     true,
+			wantErrPart: "has no matching file",
+		},
+		{
+			name:   "two files resolving to the same locale",
+			config: Configs{},
+			files: map[string]string{
+				"en-US.txt": "English notes.",
+				"en-US.md":  "Other English notes.",
+			},
+			wantErr:     true,
+			wantErrPart: "is already provided by",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.WhatsnewsDir = writeWhatsnewFiles(t, tt.files)
+			err := tt.config.validateWhatsnews()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Configs.validateWhatsnews() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), tt.wantErrPart) {
+				t.Errorf("Configs.validateWhatsnews() error = %v, want it to contain %q", err, tt.wantErrPart)
+			}
+		})
+	}
+}
+
+func TestConfigs_whatsnewsForLocales(t *testing.T) {
+	config := Configs{WhatsnewsFallbackLocale: "en-US"}
+	config.WhatsnewsDir = writeWhatsnewFiles(t, map[string]string{
+		"en-US.txt": "English notes.",
+		"fr-FR.txt": "Notes en français.",
+	})
+
+	got, err := config.whatsnewsForLocales([]string{"en-US", "fr-FR", "de-DE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"en-US": "English notes.",
+		"fr-FR": "Notes en français.",
+		"de-DE": "English notes.",
+	}
+	for locale, note := range want {
+		if got[locale] != note {
+			t.Errorf("whatsnewsForLocales()[%q] = %q, want %q", locale, got[locale], note)
+		}
+	}
+}
+
+func TestConfigs_whatsnewsForLocales_noFallback(t *testing.T) {
+	config := Configs{}
+	config.WhatsnewsDir = writeWhatsnewFiles(t, map[string]string{
+		"en-US.txt": "English notes.",
+	})
+
+	got, err := config.whatsnewsForLocales([]string{"en-US", "de-DE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := got["de-DE"]; ok {
+		t.Errorf("whatsnewsForLocales() unexpectedly filled in de-DE without a fallback locale configured")
+	}
+	if got["en-US"] != "English notes." {
+		t.Errorf("whatsnewsForLocales()[en-US] = %q, want %q", got["en-US"], "English notes.")
+	}
+}